@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	cm "github.com/chartmuseum/go-chartmuseum/pkg/chartmuseum"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/provenance"
 )
 
 type (
@@ -47,6 +50,37 @@ func initApp() *cli.App {
 			Usage:  "ChartMuseum Repo",
 			EnvVar: "CHARTMUSEUM_REPO",
 		},
+		cli.StringFlag{
+			Name:  "ca-file",
+			Value: "",
+			Usage: "verify certificates of HTTPS-enabled servers using this CA bundle",
+		},
+		cli.StringFlag{
+			Name:  "cert-file",
+			Value: "",
+			Usage: "identify HTTPS client using this SSL certificate file",
+		},
+		cli.StringFlag{
+			Name:  "key-file",
+			Value: "",
+			Usage: "identify HTTPS client using this SSL key file",
+		},
+		cli.BoolFlag{
+			Name:  "insecure",
+			Usage: "skip TLS certificate verification",
+		},
+		cli.StringFlag{
+			Name:   "username",
+			Value:  "",
+			Usage:  "ChartMuseum username",
+			EnvVar: "CHARTMUSEUM_USERNAME",
+		},
+		cli.StringFlag{
+			Name:   "password",
+			Value:  "",
+			Usage:  "ChartMuseum password",
+			EnvVar: "CHARTMUSEUM_PASSWORD",
+		},
 	}
 	app.Commands = []cli.Command{
 		cli.Command{
@@ -54,6 +88,21 @@ func initApp() *cli.App {
 			Action:    pushCommand,
 			Usage:     "Push chart-dir to server",
 			ArgsUsage: "chart-dir",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "sign",
+					Usage: "use a PGP private key to sign this package",
+				},
+				cli.StringFlag{
+					Name:  "key",
+					Usage: "name of the key to use when signing",
+				},
+				cli.StringFlag{
+					Name:  "keyring",
+					Value: defaultKeyring(),
+					Usage: "location of the secret keyring holding the signing key",
+				},
+			},
 		},
 		cli.Command{
 			Name:      "delete",
@@ -61,6 +110,88 @@ func initApp() *cli.App {
 			Usage:     "delete chart from server",
 			ArgsUsage: "chart-name version",
 		},
+		cli.Command{
+			Name:      "copy",
+			Action:    copyCommand,
+			Usage:     "Copy chart(s) from this server to another ChartMuseum server",
+			ArgsUsage: "[chart-name [version]]",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "dest-server",
+					Value:  "",
+					Usage:  "destination ChartMuseum API base `URL`",
+					EnvVar: "CHARTMUSEUM_DEST_SERVER",
+				},
+				cli.StringFlag{
+					Name:  "dest-ca-file",
+					Value: "",
+					Usage: "verify certificates of the destination HTTPS-enabled server using this CA bundle",
+				},
+				cli.StringFlag{
+					Name:  "dest-cert-file",
+					Value: "",
+					Usage: "identify HTTPS client to the destination server using this SSL certificate file",
+				},
+				cli.StringFlag{
+					Name:  "dest-key-file",
+					Value: "",
+					Usage: "identify HTTPS client to the destination server using this SSL key file",
+				},
+				cli.BoolFlag{
+					Name:  "dest-insecure",
+					Usage: "skip TLS certificate verification for the destination server",
+				},
+				cli.StringFlag{
+					Name:   "dest-username",
+					Value:  "",
+					Usage:  "ChartMuseum username for the destination server",
+					EnvVar: "CHARTMUSEUM_DEST_USERNAME",
+				},
+				cli.StringFlag{
+					Name:   "dest-password",
+					Value:  "",
+					Usage:  "ChartMuseum password for the destination server",
+					EnvVar: "CHARTMUSEUM_DEST_PASSWORD",
+				},
+				cli.BoolFlag{
+					Name:  "all-versions",
+					Usage: "copy every version of each chart, not just the latest (only applies when chart-name is omitted)",
+				},
+				cli.StringFlag{
+					Name:  "since",
+					Usage: "only copy versions matching this semver range, e.g. \">=1.2.0\" (only applies when chart-name is omitted)",
+				},
+				cli.IntFlag{
+					Name:  "concurrency",
+					Value: 4,
+					Usage: "number of chart versions to copy in parallel when mirroring a repo",
+				},
+				cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "print what would be copied without uploading anything",
+				},
+			},
+		},
+		cli.Command{
+			Name:      "inspect",
+			Action:    inspectCommand,
+			Usage:     "Show details of a chart from server",
+			ArgsUsage: "chart-name version",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "chart",
+					Usage: "show the chart's Chart.yaml",
+				},
+				cli.BoolFlag{
+					Name:  "values",
+					Usage: "show the chart's values.yaml",
+				},
+				cli.BoolFlag{
+					Name:  "readme",
+					Usage: "show the chart's README",
+				},
+			},
+		},
 	}
 	return app
 }
@@ -78,14 +209,48 @@ func initConfig(c *cli.Context) (*Config, error) {
 		return nil, errors.Errorf("Repo required if Org is set")
 	}
 
+	var opts []cm.ClientOption
+	if caFile := c.GlobalString("ca-file"); caFile != "" {
+		opts = append(opts, cm.WithCACert(caFile))
+	}
+	if certFile, keyFile := c.GlobalString("cert-file"), c.GlobalString("key-file"); certFile != "" && keyFile != "" {
+		opts = append(opts, cm.WithClientCert(certFile, keyFile))
+	}
+	if c.GlobalBool("insecure") {
+		opts = append(opts, cm.WithInsecureSkipVerify(true))
+	}
+	if username, password := c.GlobalString("username"), c.GlobalString("password"); username != "" || password != "" {
+		opts = append(opts, cm.WithBasicAuth(username, password))
+	}
+
 	// init ChartMuseum client
-	if config.Client, err = cm.NewClient(config.Server, nil); err != nil {
+	if config.Client, err = cm.NewClient(config.Server, nil, opts...); err != nil {
 		return nil, errors.Wrapf(err, "Could not create ChartMuseum client (server: %q)", config.Server)
 	}
 
 	return config, nil
 }
 
+// destClientOptions builds the ClientOptions for a `copy`/mirror destination
+// server from its --dest-* flags, mirroring initConfig's handling of the
+// source server's TLS/auth flags.
+func destClientOptions(c *cli.Context) []cm.ClientOption {
+	var opts []cm.ClientOption
+	if caFile := c.String("dest-ca-file"); caFile != "" {
+		opts = append(opts, cm.WithCACert(caFile))
+	}
+	if certFile, keyFile := c.String("dest-cert-file"), c.String("dest-key-file"); certFile != "" && keyFile != "" {
+		opts = append(opts, cm.WithClientCert(certFile, keyFile))
+	}
+	if c.Bool("dest-insecure") {
+		opts = append(opts, cm.WithInsecureSkipVerify(true))
+	}
+	if username, password := c.String("dest-username"), c.String("dest-password"); username != "" || password != "" {
+		opts = append(opts, cm.WithBasicAuth(username, password))
+	}
+	return opts
+}
+
 func pushCommand(c *cli.Context) error {
 	config, err := initConfig(c)
 	if err != nil {
@@ -102,7 +267,7 @@ func pushCommand(c *cli.Context) error {
 	ctx := context.Background()
 	//ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
 
-	response, err := packageAndUpload(ctx, config, chartPath)
+	response, err := packageAndUpload(ctx, config, chartPath, c.Bool("sign"), c.String("key"), c.String("keyring"))
 	if err != nil {
 		fmt.Printf("Error while processing %q: %s\n", chartPath, err)
 	} else if response.Saved {
@@ -144,37 +309,206 @@ func deleteCommand(c *cli.Context) error {
 	return nil
 }
 
-// packageAndUpload saves a helm chart directory to a compressed package and uploads it to chartmuseum
-func packageAndUpload(ctx context.Context, config *Config, chart string) (*cm.Response, error) {
-	tmp, err := ioutil.TempDir("", "curator-")
+func copyCommand(c *cli.Context) error {
+	config, err := initConfig(c)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Error while preparing temp Dir")
+		fmt.Printf("%s\n", err)
+		cli.ShowSubcommandHelp(c)
+		return err
 	}
 
-	defer os.RemoveAll(tmp) // clean up
+	destServer := c.String("dest-server")
+	if destServer == "" {
+		err := errors.Errorf("--dest-server is required")
+		fmt.Printf("%s\n", err)
+		cli.ShowSubcommandHelp(c)
+		return err
+	}
+	dest, err := cm.NewClient(destServer, nil, destClientOptions(c)...)
+	if err != nil {
+		return errors.Wrapf(err, "Could not create destination ChartMuseum client (server: %q)", destServer)
+	}
+
+	ctx := context.Background()
+
+	chartName := c.Args().First()
+	if chartName == "" {
+		return mirrorRepo(ctx, config, dest, c)
+	}
+
+	chartVersion := c.Args().Get(1)
+	ci := &cm.ChartInfo{
+		Name:    &chartName,
+		Version: &chartVersion,
+		Org:     &config.Org,
+		Repo:    &config.Repo,
+	}
+	if chartVersion == "" {
+		versions, err := config.Client.ChartService.GetChart(ctx, ci)
+		if err != nil || len(versions) == 0 {
+			return errors.Wrapf(err, "Could not resolve latest version of %q", chartName)
+		}
+		ci.Version = &versions[0].Version
+	}
+
+	if c.Bool("dry-run") {
+		fmt.Printf("Would copy %s to %q\n", ci, destServer)
+		return nil
+	}
 
+	response, err := config.Client.ChartService.CopyChart(ctx, dest.ChartService, ci)
+	if err != nil {
+		fmt.Printf("Error while copying %s: %s\n", ci, err)
+		return err
+	} else if response.Saved {
+		fmt.Printf("Succesfully copied %s to %q\n", ci, destServer)
+	} else {
+		fmt.Printf("Unexpected ChartMuseum response (Message = %q)\n", response.Message)
+	}
+	return nil
+}
+
+// mirrorRepo copies every chart (or every version, with --all-versions) from
+// config's repo/org scope to dest.
+func mirrorRepo(ctx context.Context, config *Config, dest *cm.Client, c *cli.Context) error {
+	ci := &cm.ChartInfo{Org: &config.Org, Repo: &config.Repo}
+	opts := cm.MirrorOptions{
+		AllVersions: c.Bool("all-versions"),
+		Since:       c.String("since"),
+		Concurrency: c.Int("concurrency"),
+		DryRun:      c.Bool("dry-run"),
+	}
+
+	result, err := config.Client.ChartService.MirrorRepo(ctx, dest.ChartService, ci, opts)
+	if err != nil {
+		return errors.Wrap(err, "Error while mirroring repo")
+	}
+
+	fmt.Printf("Copied %d chart version(s), skipped %d already present, %d failed\n",
+		len(result.Copied), len(result.Skipped), len(result.Errors))
+	for chart, chartErr := range result.Errors {
+		fmt.Printf("  %s: %s\n", chart, chartErr)
+	}
+	return nil
+}
+
+// packageAndUpload packages a helm chart directory and uploads it to chartmuseum.
+// Unsigned charts are packaged and streamed straight into the upload request
+// without touching disk. Signing still requires a concrete file to clearsign,
+// so signed charts fall back to packaging into a temp directory first.
+func packageAndUpload(ctx context.Context, config *Config, chart string, sign bool, key, keyring string) (*cm.Response, error) {
 	c, err := chartutil.LoadDir(chart)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Error while loading Chart directory: %q", chart)
 	}
+	ci := &cm.ChartInfo{
+		Name:    &c.Metadata.Name,
+		Version: &c.Metadata.Version,
+		Org:     &config.Org,
+		Repo:    &config.Repo,
+	}
+
+	if !sign {
+		return config.Client.ChartService.UploadChartDir(ctx, ci, chart)
+	}
+
+	tmp, err := ioutil.TempDir("", "curator-")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while preparing temp Dir")
+	}
+	defer os.RemoveAll(tmp) // clean up
 
 	chartPackage, err := chartutil.Save(c, tmp)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Error while packaging Chart: %q", chart)
 	}
 
+	if err := signChartPackage(chartPackage, key, keyring); err != nil {
+		return nil, errors.Wrapf(err, "Error while signing Chart: %q", chart)
+	}
+
 	f, err := os.Open(chartPackage)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Error while opening generated Chart package: %q", chartPackage)
 	}
+	return config.Client.ChartService.UploadChart(ctx, ci, f)
+}
+
+// signChartPackage clearsigns chartPackage with the named key from keyring,
+// writing the detached provenance file alongside it as "<chartPackage>.prov"
+// so UploadChart picks it up automatically, mirroring `helm package --sign`.
+func signChartPackage(chartPackage, key, keyring string) error {
+	signer, err := provenance.NewFromKeyring(keyring, key)
+	if err != nil {
+		return errors.Wrap(err, "Failed to load keyring")
+	}
+	if err := signer.DecryptKey(func(prompt string) ([]byte, error) {
+		return []byte(os.Getenv("HELM_KEY_PASSPHRASE")), nil
+	}); err != nil {
+		return errors.Wrap(err, "Failed to decrypt signing key")
+	}
+	sig, err := signer.ClearSign(chartPackage)
+	if err != nil {
+		return errors.Wrap(err, "Failed to sign chart")
+	}
+	return ioutil.WriteFile(chartPackage+".prov", []byte(sig), 0644)
+}
+
+// defaultKeyring mirrors helm's default secring.gpg location.
+func defaultKeyring() string {
+	if home := os.Getenv("GNUPGHOME"); home != "" {
+		return filepath.Join(home, "secring.gpg")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".gnupg", "secring.gpg")
+}
+
+func inspectCommand(c *cli.Context) error {
+	config, err := initConfig(c)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		cli.ShowSubcommandHelp(c)
+		return err
+	}
 
+	chartName := c.Args().First()
+	chartVersion := c.Args().Get(1)
 	ci := &cm.ChartInfo{
-		Name:    &c.Metadata.Name,
-		Version: &c.Metadata.Version,
+		Name:    &chartName,
+		Version: &chartVersion,
 		Org:     &config.Org,
 		Repo:    &config.Repo,
 	}
-	return config.Client.ChartService.UploadChart(ctx, ci, f)
+
+	ctx := context.Background()
+	details, err := config.Client.ChartService.InspectChart(ctx, ci)
+	if err != nil {
+		fmt.Printf("Error while inspecting %s: %s\n", ci, err)
+		return err
+	}
+
+	showChart := c.Bool("chart")
+	showValues := c.Bool("values")
+	showReadme := c.Bool("readme")
+	showAll := !showChart && !showValues && !showReadme
+
+	if showAll || showChart {
+		out, err := yaml.Marshal(details.Metadata)
+		if err != nil {
+			return errors.Wrap(err, "Failed to render Chart.yaml")
+		}
+		fmt.Printf("---# Source: Chart.yaml\n%s\n", out)
+	}
+	if showAll || showValues {
+		out, err := yaml.Marshal(details.Values)
+		if err != nil {
+			return errors.Wrap(err, "Failed to render values.yaml")
+		}
+		fmt.Printf("---# Source: values.yaml\n%s\n", out)
+	}
+	if showAll || showReadme {
+		fmt.Printf("---# Source: README.md\n%s\n", details.Readme)
+	}
+	return nil
 }
 
 func main() {
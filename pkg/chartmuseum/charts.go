@@ -2,11 +2,15 @@ package chartmuseum
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/repo"
 )
 
 type (
@@ -38,32 +42,156 @@ func (c ChartInfo) String() string {
 // related methods of the ChartMuseum API.
 type ChartService service
 
-// UploadChart uploads a Helm chart to a ChartMuseum server
-func (s *ChartService) UploadChart(ctx context.Context, c *ChartInfo, file *os.File) (*Response, error) {
-	u := "api/charts"
+// scopedPrefix returns the "org/repo/" URL prefix implied by c's Org/Repo
+// fields, centralizing the precedence rules (Org requires Repo, Repo alone
+// is also valid, neither means the top-level/default repo) shared by every
+// route below.
+func scopedPrefix(c *ChartInfo) (string, error) {
 	if *c.Org != "" {
 		if *c.Repo == "" {
-			return nil, errors.Errorf("Repo required if Org is provided")
+			return "", errors.Errorf("Repo required if Org is provided")
 		}
-		u = fmt.Sprintf("api/%s/%s/charts", *c.Org, *c.Repo)
-	} else if *c.Repo != "" {
-		u = fmt.Sprintf("api/%s/charts", *c.Repo)
+		return fmt.Sprintf("%s/%s/", *c.Org, *c.Repo), nil
+	}
+	if *c.Repo != "" {
+		return fmt.Sprintf("%s/", *c.Repo), nil
+	}
+	return "", nil
+}
+
+// UploadChart uploads a Helm chart to a ChartMuseum server. If a sibling
+// provenance file (<chart>.tgz.prov") is found alongside file, it is
+// uploaded together with the chart in a single multipart/form-data request.
+func (s *ChartService) UploadChart(ctx context.Context, c *ChartInfo, file *os.File) (*Response, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	u := prefix + "api/charts"
+	prov, err := os.Open(file.Name() + ".prov")
+	if err == nil {
+		defer prov.Close()
+		return s.uploadChartWithProvenanceHelper(ctx, u, file, prov)
 	}
 	return s.uploadChartHelper(ctx, u, file)
 }
 
 // DeleteChart deletes a Helm chart from a ChartMuseum server
 func (s *ChartService) DeleteChart(ctx context.Context, c *ChartInfo) (*Response, error) {
-	u := fmt.Sprintf("api/charts/%s/%s", *c.Name, *c.Version)
-	if *c.Org != "" {
-		if *c.Repo == "" {
-			return nil, errors.Errorf("Repo required if Org is provided")
-		}
-		u = fmt.Sprintf("api/%s/%s/charts/%s/%s", *c.Org, *c.Repo, *c.Name, *c.Version)
-	} else if *c.Repo != "" {
-		u = fmt.Sprintf("api/%s/charts/%s/%s", *c.Repo, *c.Name, *c.Version)
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	return s.deleteChartHelper(ctx, fmt.Sprintf("%sapi/charts/%s/%s", prefix, *c.Name, *c.Version))
+}
+
+// GetIndex fetches and parses the repository index (index.yaml) for the
+// scope (Org/Repo) described by c. Name and Version are ignored.
+func (s *ChartService) GetIndex(ctx context.Context, c *ChartInfo) (*repo.IndexFile, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("GET", prefix+"index.yaml", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating index request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do index request")
+	}
+	index := &repo.IndexFile{}
+	if err := yaml.Unmarshal(resp.Body, index); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse index.yaml")
+	}
+	return index, nil
+}
+
+// ListCharts lists every chart and version known to the server, keyed by
+// chart name.
+func (s *ChartService) ListCharts(ctx context.Context, c *ChartInfo) (map[string]repo.ChartVersions, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("GET", prefix+"api/charts", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating list charts request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do list charts request")
+	}
+	charts := map[string]repo.ChartVersions{}
+	if err := json.Unmarshal(resp.Body, &charts); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse chart list")
 	}
-	return s.deleteChartHelper(ctx, u)
+	return charts, nil
+}
+
+// GetChart returns every version known to the server for c.Name.
+func (s *ChartService) GetChart(ctx context.Context, c *ChartInfo) (repo.ChartVersions, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("%sapi/charts/%s", prefix, *c.Name), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating get chart request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do get chart request")
+	}
+	var versions repo.ChartVersions
+	if err := json.Unmarshal(resp.Body, &versions); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse chart versions")
+	}
+	return versions, nil
+}
+
+// GetChartVersion returns the single chart version described by c.Name and
+// c.Version.
+func (s *ChartService) GetChartVersion(ctx context.Context, c *ChartInfo) (*repo.ChartVersion, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest("GET", fmt.Sprintf("%sapi/charts/%s/%s", prefix, *c.Name, *c.Version), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating get chart version request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do get chart version request")
+	}
+	version := &repo.ChartVersion{}
+	if err := json.Unmarshal(resp.Body, version); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse chart version")
+	}
+	return version, nil
+}
+
+// DownloadChart downloads the chart tarball described by c.Name and
+// c.Version, returning its raw bytes. The download URL is resolved from the
+// chart version's metadata, as published in index.yaml.
+func (s *ChartService) DownloadChart(ctx context.Context, c *ChartInfo) ([]byte, error) {
+	version, err := s.GetChartVersion(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(version.URLs) == 0 {
+		return nil, errors.Errorf("Chart version %s has no download URLs", c)
+	}
+	req, err := s.client.NewRequest("GET", version.URLs[0], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating download chart request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do download chart request")
+	}
+	return resp.Body, nil
 }
 
 // deleteChartHelper prepares and executes the upload request
@@ -88,6 +216,29 @@ func (s *ChartService) uploadChartHelper(ctx context.Context, u string, file *os
 	return resp, nil
 }
 
+// uploadChartWithProvenanceHelper prepares and executes a combined chart+prov upload request
+func (s *ChartService) uploadChartWithProvenanceHelper(ctx context.Context, u string, chart, prov *os.File) (*Response, error) {
+	stat, err := chart.Stat()
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to access file")
+	}
+	if stat.IsDir() {
+		return nil, errors.New("Chart to upload can't be a directory")
+	}
+	req, err := s.client.NewMultipartUploadRequest(u,
+		UploadPart{Field: "chart", FileName: filepath.Base(chart.Name()), Reader: chart},
+		UploadPart{Field: "prov", FileName: filepath.Base(prov.Name()), Reader: prov},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating upload request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return resp, errors.Wrap(err, "Failed to do upload request")
+	}
+	return resp, nil
+}
+
 // detectContentType returns a valid content-type and "application/octet-stream" if error or no match
 func detectContentType(file *os.File) (string, error) {
 	// Only the first 512 bytes are used to sniff the content type.
@@ -0,0 +1,138 @@
+package chartmuseum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"healthy":true}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	resp, err := c.HealthCheck(context.Background())
+	if err != nil {
+		t.Fatalf("HealthCheck failed: %v", err)
+	}
+	if !resp.Healthy {
+		t.Error("expected Healthy to be true")
+	}
+}
+
+func TestGetIndex(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/index.yaml" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Write([]byte("apiVersion: v1\nentries:\n  mychart:\n  - name: mychart\n    version: 1.0.0\n"))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	empty := ""
+	index, err := c.ChartService.GetIndex(context.Background(), &ChartInfo{Org: &empty, Repo: &empty})
+	if err != nil {
+		t.Fatalf("GetIndex failed: %v", err)
+	}
+	versions, ok := index.Entries["mychart"]
+	if !ok || len(versions) != 1 || versions[0].Version != "1.0.0" {
+		t.Errorf("unexpected index contents: %+v", index.Entries)
+	}
+}
+
+func TestListCharts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/charts" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"mychart":[{"name":"mychart","version":"1.0.0"}]}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	empty := ""
+	charts, err := c.ChartService.ListCharts(context.Background(), &ChartInfo{Org: &empty, Repo: &empty})
+	if err != nil {
+		t.Fatalf("ListCharts failed: %v", err)
+	}
+	versions, ok := charts["mychart"]
+	if !ok || len(versions) != 1 || versions[0].Version != "1.0.0" {
+		t.Errorf("unexpected chart list: %+v", charts)
+	}
+}
+
+func TestGetChartVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/charts/mychart/1.0.0" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"mychart","version":"1.0.0","urls":["charts/mychart-1.0.0.tgz"]}`))
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	name, version, empty := "mychart", "1.0.0", ""
+	v, err := c.ChartService.GetChartVersion(context.Background(), &ChartInfo{Name: &name, Version: &version, Org: &empty, Repo: &empty})
+	if err != nil {
+		t.Fatalf("GetChartVersion failed: %v", err)
+	}
+	if v.Name != "mychart" || v.Version != "1.0.0" || len(v.URLs) != 1 {
+		t.Errorf("unexpected chart version: %+v", v)
+	}
+}
+
+func TestDownloadChart(t *testing.T) {
+	const tarball = "not-really-a-tarball"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts/mychart/1.0.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"mychart","version":"1.0.0","urls":["charts/mychart-1.0.0.tgz"]}`))
+		case "/charts/mychart-1.0.0.tgz":
+			w.Write([]byte(tarball))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	name, version, empty := "mychart", "1.0.0", ""
+	data, err := c.ChartService.DownloadChart(context.Background(), &ChartInfo{Name: &name, Version: &version, Org: &empty, Repo: &empty})
+	if err != nil {
+		t.Fatalf("DownloadChart failed: %v", err)
+	}
+	if string(data) != tarball {
+		t.Errorf("expected %q, got %q", tarball, data)
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 const (
@@ -28,6 +30,15 @@ type (
 
 		httpClient *http.Client
 
+		// auth, if set, is applied to every outgoing request by NewRequest,
+		// NewUploadRequest and NewMultipartUploadRequest. Configured via
+		// WithBasicAuth/WithBearerToken.
+		auth func(*http.Request)
+
+		// retry controls how Do retries failed requests. Configured via
+		// WithRetry; defaults to DefaultRetryPolicy().
+		retry RetryPolicy
+
 		common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 		ChartService *ChartService
@@ -46,13 +57,21 @@ type (
 		Saved   bool   `json:"saved,omitempty"`
 		Deleted bool   `json:"deleted,omitempty"`
 		Healthy bool   `json:"healthy,omitempty"`
+
+		// Body holds the raw response body, already drained from the
+		// underlying http.Response. Services that don't speak the
+		// ChartMuseum JSON envelope (index.yaml, chart tarballs) parse
+		// this themselves instead of adding one-off Do variants.
+		Body []byte `json:"-"`
 	}
 )
 
 // NewClient returns a new ChartMuseum API client with provided base URL
 // If trailing slash is missing from base URL, one is added automatically.
 // If a nil httpClient is provided, http.DefaultClient will be used.
-func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
+// Additional ClientOptions (TLS settings, authentication, ...) can be
+// supplied via opts.
+func NewClient(baseURL string, httpClient *http.Client, opts ...ClientOption) (*Client, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("ChartMuseum API - base URL can not be blank")
 	}
@@ -64,17 +83,41 @@ func NewClient(baseURL string, httpClient *http.Client) (*Client, error) {
 		baseEndpoint.Path += "/"
 	}
 	if httpClient == nil {
-		httpClient = http.DefaultClient
+		httpClient = &http.Client{}
 	}
 
-	c := &Client{httpClient: httpClient, BaseURL: baseEndpoint, UserAgent: userAgent}
+	c := &Client{httpClient: httpClient, BaseURL: baseEndpoint, UserAgent: userAgent, retry: DefaultRetryPolicy()}
 	c.BaseURL = baseEndpoint
 	c.common.client = c
 	c.ChartService = (*ChartService)(&c.common)
 
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+// applyAuth sets whatever credentials were configured via WithBasicAuth or
+// WithBearerToken on req.
+func (c *Client) applyAuth(req *http.Request) {
+	if c.auth != nil {
+		c.auth(req)
+	}
+}
+
+// HealthCheck hits the ChartMuseum /health endpoint and reports whether the
+// server considers itself healthy via the returned Response's Healthy field.
+func (c *Client) HealthCheck(ctx context.Context) (*Response, error) {
+	req, err := c.NewRequest("GET", "health", nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(ctx, req)
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr,
 // in which case it is resolved relative to the BaseURL of the Client.
 // Relative URLs should always be specified without a preceding slash. If
@@ -112,12 +155,15 @@ func (c *Client) NewRequest(method, urlStr string, body interface{}) (*http.Requ
 	if c.UserAgent != "" {
 		req.Header.Set("User-Agent", c.UserAgent)
 	}
+	c.applyAuth(req)
 	return req, nil
 }
 
 // NewUploadRequest creates an upload request. A relative URL can be provided in
 // urlStr, in which case it is resolved relative to the BaseURL of the Client.
-// Relative URLs should always be specified without a preceding slash.
+// Relative URLs should always be specified without a preceding slash. A size
+// of -1 means the length of reader is unknown; the request is then sent with
+// Transfer-Encoding: chunked instead of a Content-Length.
 func (c *Client) NewUploadRequest(urlStr string, reader io.Reader, size int64, mediaType string) (*http.Request, error) {
 	if !strings.HasSuffix(c.BaseURL.Path, "/") {
 		return nil, fmt.Errorf("base URL must have a trailing slash, but %q does not", c.BaseURL)
@@ -127,22 +173,148 @@ func (c *Client) NewUploadRequest(urlStr string, reader io.Reader, size int64, m
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", u.String(), reader)
+	body := reader
+	if _, ok := reader.(io.ReadSeeker); ok {
+		// Keep the transport from closing a rewindable reader (such as an
+		// *os.File) after the first attempt, so GetBody can rewind it.
+		body = ioutil.NopCloser(reader)
+	}
+	req, err := http.NewRequest("POST", u.String(), body)
 	if err != nil {
 		return nil, err
 	}
-	req.ContentLength = size
+	if size < 0 {
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	} else {
+		req.ContentLength = size
+	}
+
+	// Allow Do to replay the body on retry. reader is typically an *os.File
+	// or *bytes.Reader, both of which can be rewound.
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		req.GetBody = func() (io.ReadCloser, error) {
+			if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			return ioutil.NopCloser(seeker), nil
+		}
+	}
 
 	req.Header.Set("Content-Type", mediaType)
 	req.Header.Set("User-Agent", c.UserAgent)
+	c.applyAuth(req)
 	return req, nil
 }
 
-// Do sends an API request and returns the API response.
+type (
+	// UploadPart describes a single multipart/form-data field for
+	// NewMultipartUploadRequest: its field name, file name, and content.
+	UploadPart struct {
+		Field    string
+		FileName string
+		Reader   io.Reader
+	}
+)
+
+// NewMultipartUploadRequest creates a multipart/form-data upload request with
+// one part per element of parts, e.g. a "chart" field and an optional "prov"
+// field. Parts are streamed into the request body through an io.Pipe rather
+// than buffered, so callers can pass readers backed by files, in-memory
+// archives, or anything else. A relative URL can be provided in urlStr,
+// resolved the same way as NewRequest.
+func (c *Client) NewMultipartUploadRequest(urlStr string, parts ...UploadPart) (*http.Request, error) {
+	if !strings.HasSuffix(c.BaseURL.Path, "/") {
+		return nil, fmt.Errorf("base URL must have a trailing slash, but %q does not", c.BaseURL)
+	}
+	u, err := c.BaseURL.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeMultipartParts(w, pw, parts))
+	}()
+
+	req, err := http.NewRequest("POST", u.String(), pr)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = -1
+	req.TransferEncoding = []string{"chunked"}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("User-Agent", c.UserAgent)
+	c.applyAuth(req)
+	return req, nil
+}
+
+// writeMultipartParts writes parts into w, the multipart.Writer wrapping pw,
+// and is run on its own goroutine by NewMultipartUploadRequest.
+func writeMultipartParts(w *multipart.Writer, pw *io.PipeWriter, parts []UploadPart) error {
+	for _, part := range parts {
+		fw, err := w.CreateFormFile(part.Field, part.FileName)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(fw, part.Reader); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+// Do sends an API request and returns the API response, retrying according
+// to the Client's RetryPolicy (see WithRetry) on transient failures. On a
+// non-2xx response, the returned error is a *APIError.
 //
 // The provided ctx must be non-nil. If it is canceled or times out,
 // ctx.Err() will be returned.
 func (c *Client) Do(ctx context.Context, req *http.Request) (*Response, error) {
+	policy := c.retry
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+
+	var resp *Response
+	var err error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if req.Body != nil {
+				if req.GetBody == nil {
+					break
+				}
+				body, bodyErr := req.GetBody()
+				if bodyErr != nil {
+					break
+				}
+				req.Body = body
+			}
+		}
+
+		resp, err = c.doOnce(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if ctx.Err() != nil {
+			return resp, ctx.Err()
+		}
+		if attempt == policy.MaxAttempts-1 || !policy.shouldRetry(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-time.After(policy.backoff(attempt, resp)):
+		}
+	}
+	return resp, err
+}
+
+// doOnce performs a single attempt of req, without retrying.
+func (c *Client) doOnce(ctx context.Context, req *http.Request) (*Response, error) {
 	req = req.WithContext(ctx)
 
 	resp, err := c.httpClient.Do(req)
@@ -165,10 +337,16 @@ func parseResponse(r *http.Response) (*Response, error) {
 	data, err := ioutil.ReadAll(r.Body)
 	defer r.Body.Close()
 	if err == nil && data != nil {
+		response.Body = data
 		json.Unmarshal(data, response)
 	}
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return response, nil
 	}
-	return response, fmt.Errorf(response.Error)
+	return response, &APIError{
+		StatusCode: r.StatusCode,
+		Message:    response.Message,
+		Err:        response.Error,
+		RequestID:  r.Header.Get("X-Request-Id"),
+	}
 }
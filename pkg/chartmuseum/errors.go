@@ -0,0 +1,28 @@
+package chartmuseum
+
+import "fmt"
+
+// APIError is returned whenever the ChartMuseum server responds with a
+// non-2xx status code, letting callers errors.As on it instead of matching
+// against an error string.
+type APIError struct {
+	// StatusCode is the HTTP status code returned by the server.
+	StatusCode int
+	// Message is the ChartMuseum "message" field, if any.
+	Message string
+	// Err is the ChartMuseum "error" field, if any.
+	Err string
+	// RequestID is the X-Request-Id response header, if the server set one.
+	RequestID string
+}
+
+func (e *APIError) Error() string {
+	msg := e.Err
+	if msg == "" {
+		msg = e.Message
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("chartmuseum: %d %s (request-id: %s)", e.StatusCode, msg, e.RequestID)
+	}
+	return fmt.Sprintf("chartmuseum: %d %s", e.StatusCode, msg)
+}
@@ -0,0 +1,72 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+type (
+	// ChartDetails holds the parsed contents of a downloaded chart archive.
+	ChartDetails struct {
+		// Metadata is the chart's Chart.yaml.
+		Metadata *chart.Metadata
+		// Values holds the parsed contents of values.yaml.
+		Values map[string]interface{}
+		// Readme holds the contents of README.md, if the chart has one.
+		Readme string
+		// Dependencies lists the chart's declared dependencies, as read
+		// from requirements.yaml.
+		Dependencies []*chart.Dependency
+		// Files holds the chart's other files as loaded by chartutil,
+		// keyed by path relative to the chart root. README.md,
+		// Chart.yaml, values.yaml, templates and subcharts are not
+		// included.
+		Files map[string][]byte
+	}
+)
+
+// InspectChart downloads the chart archive described by c and parses its
+// Chart.yaml, values.yaml, README.md and requirements.yaml in memory,
+// without needing a local Helm install.
+func (s *ChartService) InspectChart(ctx context.Context, c *ChartInfo) (*ChartDetails, error) {
+	data, err := s.DownloadChart(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := chartutil.LoadArchive(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to load chart archive for %s", c)
+	}
+
+	details := &ChartDetails{
+		Metadata: archive.Metadata,
+		Values:   map[string]interface{}{},
+		Files:    map[string][]byte{},
+	}
+
+	if archive.Values != nil && archive.Values.Raw != "" {
+		if err := yaml.Unmarshal([]byte(archive.Values.Raw), &details.Values); err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse values.yaml for %s", c)
+		}
+	}
+
+	for _, f := range archive.Files {
+		if f.TypeUrl == "README.md" {
+			details.Readme = string(f.Value)
+			continue
+		}
+		details.Files[f.TypeUrl] = f.Value
+	}
+
+	if archive.Metadata != nil {
+		details.Dependencies = archive.Metadata.Dependencies
+	}
+
+	return details, nil
+}
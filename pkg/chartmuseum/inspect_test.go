@@ -0,0 +1,67 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestInspectChart(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "mychart", Version: "1.0.0"},
+		Values:   &chart.Config{Raw: "replicaCount: 1\n"},
+		Files: []*any.Any{
+			{TypeUrl: "README.md", Value: []byte("# mychart")},
+			{TypeUrl: "NOTES.txt", Value: []byte("install notes")},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeChartArchive(&buf, c); err != nil {
+		t.Fatalf("writeChartArchive failed: %v", err)
+	}
+	tarball := buf.Bytes()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts/mychart/1.0.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"mychart","version":"1.0.0","urls":["charts/mychart-1.0.0.tgz"]}`))
+		case "/charts/mychart-1.0.0.tgz":
+			w.Write(tarball)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	name, version, empty := "mychart", "1.0.0", ""
+	ci := &ChartInfo{Name: &name, Version: &version, Org: &empty, Repo: &empty}
+
+	details, err := client.ChartService.InspectChart(context.Background(), ci)
+	if err != nil {
+		t.Fatalf("InspectChart failed: %v", err)
+	}
+	if details.Metadata == nil || details.Metadata.Name != "mychart" {
+		t.Errorf("unexpected metadata: %+v", details.Metadata)
+	}
+	if details.Values["replicaCount"] != 1 {
+		t.Errorf("unexpected values: %+v", details.Values)
+	}
+	if details.Readme != "# mychart" {
+		t.Errorf("unexpected readme: %q", details.Readme)
+	}
+	if string(details.Files["NOTES.txt"]) != "install notes" {
+		t.Errorf("unexpected files: %+v", details.Files)
+	}
+}
@@ -0,0 +1,154 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+type (
+	// MirrorOptions controls how MirrorRepo selects and copies chart
+	// versions from a source ChartMuseum to a destination.
+	MirrorOptions struct {
+		// AllVersions copies every version of every chart found on the
+		// source. If false, only the latest (first-listed) version of
+		// each chart is copied.
+		AllVersions bool
+		// Since restricts copied versions to those matching this semver
+		// range, e.g. ">=1.2.0". Ignored if empty.
+		Since string
+		// Concurrency is the number of chart versions copied in parallel.
+		// Defaults to 1 if <= 0.
+		Concurrency int
+		// DryRun reports what would be copied without uploading anything.
+		DryRun bool
+	}
+
+	// MirrorResult summarizes the outcome of a MirrorRepo call.
+	MirrorResult struct {
+		Copied  []string
+		Skipped []string
+		Errors  map[string]error
+	}
+)
+
+// CopyChart downloads the chart version described by c from the source
+// server and re-uploads it (plus its provenance file, if present) to dst,
+// streaming both straight from memory without ever touching disk.
+func (s *ChartService) CopyChart(ctx context.Context, dst *ChartService, c *ChartInfo) (*Response, error) {
+	chartData, err := s.DownloadChart(ctx, c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to download %s from source", c)
+	}
+
+	resp, err := dst.UploadChartReader(ctx, c, bytes.NewReader(chartData), int64(len(chartData)))
+	if err != nil {
+		return resp, err
+	}
+
+	if provData, err := s.DownloadProvenance(ctx, c); err == nil {
+		if _, err := dst.UploadProvenance(ctx, c, bytes.NewReader(provData)); err != nil {
+			return resp, errors.Wrap(err, "Failed to copy provenance file")
+		}
+	}
+
+	return resp, nil
+}
+
+// MirrorRepo copies every chart version matching opts from the repo/org
+// described by c on the source server to dst, skipping versions already
+// present there.
+func (s *ChartService) MirrorRepo(ctx context.Context, dst *ChartService, c *ChartInfo, opts MirrorOptions) (*MirrorResult, error) {
+	charts, err := s.ListCharts(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list charts on source")
+	}
+
+	var constraint *semver.Constraints
+	if opts.Since != "" {
+		constraint, err = semver.NewConstraint(opts.Since)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid --since constraint %q", opts.Since)
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	type target struct {
+		name, version string
+	}
+	var targets []target
+	for name, versions := range charts {
+		for i, v := range versions {
+			if !opts.AllVersions && i > 0 {
+				break
+			}
+			if constraint != nil {
+				sv, err := semver.NewVersion(v.Version)
+				if err != nil || !constraint.Check(sv) {
+					continue
+				}
+			}
+			targets = append(targets, target{name: name, version: v.Version})
+		}
+	}
+
+	result := &MirrorResult{Errors: map[string]error{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, t := range targets {
+		name, version := t.name, t.version
+		ci := &ChartInfo{Name: &name, Version: &version, Org: c.Org, Repo: c.Repo}
+
+		if _, err := dst.GetChartVersion(ctx, ci); err == nil {
+			mu.Lock()
+			result.Skipped = append(result.Skipped, ci.String())
+			mu.Unlock()
+			continue
+		} else {
+			var apiErr *APIError
+			if !stderrors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+				mu.Lock()
+				result.Errors[ci.String()] = errors.Wrap(err, "Failed to probe destination")
+				mu.Unlock()
+				continue
+			}
+		}
+
+		if opts.DryRun {
+			mu.Lock()
+			result.Copied = append(result.Copied, ci.String())
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := s.CopyChart(ctx, dst, ci)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Errors[ci.String()] = err
+			} else {
+				result.Copied = append(result.Copied, ci.String())
+			}
+		}()
+	}
+	wg.Wait()
+
+	return result, nil
+}
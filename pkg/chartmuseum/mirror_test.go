@@ -0,0 +1,61 @@
+package chartmuseum
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMirrorRepoSkipsPresentButFailsOnServerError(t *testing.T) {
+	srcSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"present":[{"name":"present","version":"1.0.0"}],"broken":[{"name":"broken","version":"1.0.0"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srcSrv.Close()
+
+	dstSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts/present/1.0.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"present","version":"1.0.0"}`))
+		case "/api/charts/broken/1.0.0":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer dstSrv.Close()
+
+	src, err := NewClient(srcSrv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("failed to create source client: %v", err)
+	}
+	dst, err := NewClient(dstSrv.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("failed to create destination client: %v", err)
+	}
+
+	empty := ""
+	result, err := src.ChartService.MirrorRepo(context.Background(), dst.ChartService, &ChartInfo{Org: &empty, Repo: &empty}, MirrorOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("MirrorRepo failed: %v", err)
+	}
+
+	if len(result.Skipped) != 1 || result.Skipped[0] != "present-1.0.0" {
+		t.Errorf("expected present-1.0.0 to be skipped, got %v", result.Skipped)
+	}
+	if _, ok := result.Errors["broken-1.0.0"]; !ok {
+		t.Errorf("expected broken-1.0.0 to be recorded as an error, got %v", result.Errors)
+	}
+	for _, copied := range result.Copied {
+		if copied == "broken-1.0.0" {
+			t.Errorf("broken-1.0.0 should not have been reported as copied after a 500 probing the destination")
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package chartmuseum
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// ClientOption configures optional Client behavior, such as TLS settings or
+// how outgoing requests authenticate with the server. Pass one or more to
+// NewClient.
+type ClientOption func(*Client) error
+
+// WithTLSConfig sets the TLS configuration used for HTTPS connections to the
+// ChartMuseum server, replacing any TLS settings applied by earlier options.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(c *Client) error {
+		t, err := c.transport()
+		if err != nil {
+			return err
+		}
+		t.TLSClientConfig = cfg.Clone()
+		return nil
+	}
+}
+
+// WithCACert configures the Client to verify the server's certificate
+// against the CA bundle found at path, instead of the system's default
+// trust store.
+func WithCACert(path string) ClientOption {
+	return func(c *Client) error {
+		pemData, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read CA cert %q", path)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemData) {
+			return errors.Errorf("Failed to parse CA cert %q", path)
+		}
+		t, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		t.RootCAs = pool
+		return nil
+	}
+}
+
+// WithClientCert configures the Client to present the given certificate/key
+// pair to the server, for mTLS-enabled ChartMuseum deployments.
+func WithClientCert(certFile, keyFile string) ClientOption {
+	return func(c *Client) error {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to load client cert/key pair (%q, %q)", certFile, keyFile)
+		}
+		t, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		t.Certificates = append(t.Certificates, cert)
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables TLS certificate verification. Only
+// intended for testing against servers with self-signed certificates.
+func WithInsecureSkipVerify(skip bool) ClientOption {
+	return func(c *Client) error {
+		t, err := c.tlsConfig()
+		if err != nil {
+			return err
+		}
+		t.InsecureSkipVerify = skip
+		return nil
+	}
+}
+
+// WithBasicAuth configures the Client to authenticate every request with
+// HTTP basic auth.
+func WithBasicAuth(username, password string) ClientOption {
+	return func(c *Client) error {
+		c.auth = func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		}
+		return nil
+	}
+}
+
+// WithBearerToken configures the Client to authenticate every request with
+// an "Authorization: Bearer <token>" header.
+func WithBearerToken(token string) ClientOption {
+	return func(c *Client) error {
+		c.auth = func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return nil
+	}
+}
+
+// transport returns the *http.Transport backing the Client's http.Client,
+// cloning it (or http.DefaultTransport, if none was set) so that TLS options
+// never mutate a *http.Transport the caller might still be using elsewhere.
+// The clone replaces c.httpClient.Transport. Each call clones the current
+// transport again; settings applied by earlier options carry over because
+// Clone copies TLSClientConfig.
+func (c *Client) transport() (*http.Transport, error) {
+	switch t := c.httpClient.Transport.(type) {
+	case *http.Transport:
+		clone := t.Clone()
+		c.httpClient.Transport = clone
+		return clone, nil
+	case nil:
+		clone := http.DefaultTransport.(*http.Transport).Clone()
+		c.httpClient.Transport = clone
+		return clone, nil
+	default:
+		return nil, errors.New("ChartMuseum API - httpClient.Transport must be an *http.Transport to use TLS options")
+	}
+}
+
+// tlsConfig returns the *tls.Config backing the Client's http.Transport,
+// creating a private Transport/TLS config for the Client the first time a
+// TLS option is applied.
+func (c *Client) tlsConfig() (*tls.Config, error) {
+	transport, err := c.transport()
+	if err != nil {
+		return nil, err
+	}
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	return transport.TLSClientConfig, nil
+}
@@ -0,0 +1,36 @@
+package chartmuseum
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestWithInsecureSkipVerifyClonesSharedTransport(t *testing.T) {
+	shared := &http.Transport{}
+	httpClient := &http.Client{Transport: shared}
+
+	if _, err := NewClient("http://example.com/", httpClient, WithInsecureSkipVerify(true)); err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	// Transport.Clone() has a documented side effect of lazily populating
+	// the *original* transport's TLSClientConfig (via its nextProtoOnce)
+	// for HTTP/2 bookkeeping, so asserting shared.TLSClientConfig == nil
+	// doesn't hold. What matters is that the shared transport's
+	// InsecureSkipVerify was never set, and that the Client ended up with
+	// its own, distinct *http.Transport.
+	if shared.TLSClientConfig != nil && shared.TLSClientConfig.InsecureSkipVerify {
+		t.Error("WithInsecureSkipVerify mutated the caller's shared *http.Transport in place")
+	}
+
+	got, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("httpClient.Transport is %T, want *http.Transport", httpClient.Transport)
+	}
+	if got == shared {
+		t.Error("Client did not clone the caller's shared *http.Transport")
+	}
+	if got.TLSClientConfig == nil || !got.TLSClientConfig.InsecureSkipVerify {
+		t.Error("InsecureSkipVerify was not applied to the cloned transport")
+	}
+}
@@ -0,0 +1,124 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+type (
+	// VerificationResult describes the outcome of verifying a chart's
+	// provenance file against a PGP keyring.
+	VerificationResult struct {
+		// SignedBy is the key that produced the provenance file's signature.
+		SignedBy *openpgp.Entity
+		// FileHash is the sha256 digest recorded for the chart tarball in
+		// its provenance file.
+		FileHash string
+		// FileName is the chart tarball's file name, as recorded in the
+		// provenance file.
+		FileName string
+	}
+)
+
+// UploadProvenance uploads a Helm provenance (.prov) file to a ChartMuseum server
+func (s *ChartService) UploadProvenance(ctx context.Context, c *ChartInfo, r io.Reader) (*Response, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewUploadRequest(prefix+"api/prov", r, -1, "application/octet-stream")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating provenance upload request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return resp, errors.Wrap(err, "Failed to do provenance upload request")
+	}
+	return resp, nil
+}
+
+// DownloadProvenance downloads the provenance (.prov) file for the chart
+// version described by c.Name and c.Version, returning its raw bytes.
+func (s *ChartService) DownloadProvenance(ctx context.Context, c *ChartInfo) ([]byte, error) {
+	version, err := s.GetChartVersion(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(version.URLs) == 0 {
+		return nil, errors.Errorf("Chart version %s has no download URLs", c)
+	}
+	req, err := s.client.NewRequest("GET", version.URLs[0]+".prov", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating provenance download request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to do provenance download request")
+	}
+	return resp.Body, nil
+}
+
+// VerifyChart downloads the chart tarball and its provenance file for c,
+// validates the provenance file's OpenPGP clearsign signature against
+// keyring, and refuses the chart if its embedded "<file>: sha256:<sum>" line
+// does not match the downloaded tarball.
+func (s *ChartService) VerifyChart(ctx context.Context, c *ChartInfo, keyring openpgp.KeyRing) (*VerificationResult, error) {
+	chartData, err := s.DownloadChart(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to download chart")
+	}
+	provData, err := s.DownloadProvenance(ctx, c)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to download provenance file")
+	}
+
+	block, _ := clearsign.Decode(provData)
+	if block == nil {
+		return nil, errors.Errorf("%s: provenance file is not OpenPGP clearsigned", c)
+	}
+	signer, err := openpgp.CheckDetachedSignature(keyring, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to verify provenance signature")
+	}
+
+	fileName, fileHash, err := parseProvenanceSum(block.Plaintext)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", c)
+	}
+
+	sum := sha256.Sum256(chartData)
+	digest := hex.EncodeToString(sum[:])
+	if digest != fileHash {
+		return nil, errors.Errorf("%s: sha256 sum does not match, expected %q, got %q", c, fileHash, digest)
+	}
+
+	return &VerificationResult{
+		SignedBy: signer,
+		FileHash: fileHash,
+		FileName: fileName,
+	}, nil
+}
+
+// parseProvenanceSum extracts the "<file>: sha256:<sum>" checksum line from
+// a provenance file's signed plaintext (YAML metadata followed by a "files:"
+// checksum block, per the Helm provenance format).
+func parseProvenanceSum(plaintext []byte) (fileName, fileHash string, err error) {
+	const marker = ": sha256:"
+	for _, line := range strings.Split(string(plaintext), "\n") {
+		line = strings.TrimSpace(line)
+		idx := strings.Index(line, marker)
+		if idx < 0 {
+			continue
+		}
+		return strings.Trim(line[:idx], `"`), strings.TrimSpace(line[idx+len(marker):]), nil
+	}
+	return "", "", errors.New("Provenance file has no sha256 checksum entry")
+}
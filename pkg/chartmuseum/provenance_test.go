@@ -0,0 +1,166 @@
+package chartmuseum
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/clearsign"
+)
+
+func TestParseProvenanceSum(t *testing.T) {
+	tests := []struct {
+		name      string
+		plaintext string
+		wantFile  string
+		wantHash  string
+		wantErr   bool
+	}{
+		{
+			name:      "quoted file name",
+			plaintext: "name: mychart\nversion: 1.0.0\n...\nfiles:\n  \"mychart-1.0.0.tgz\": sha256:abc123\n",
+			wantFile:  "mychart-1.0.0.tgz",
+			wantHash:  "abc123",
+		},
+		{
+			name:      "unquoted file name",
+			plaintext: "files:\n  mychart-1.0.0.tgz: sha256:def456\n",
+			wantFile:  "mychart-1.0.0.tgz",
+			wantHash:  "def456",
+		},
+		{
+			name:      "no checksum entry",
+			plaintext: "name: mychart\nversion: 1.0.0\n",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, hash, err := parseProvenanceSum([]byte(tt.plaintext))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseProvenanceSum failed: %v", err)
+			}
+			if file != tt.wantFile || hash != tt.wantHash {
+				t.Errorf("got (%q, %q), want (%q, %q)", file, hash, tt.wantFile, tt.wantHash)
+			}
+		})
+	}
+}
+
+// signProvenance builds a clearsigned provenance file over plaintext using
+// signer's private key, returning the signed bytes.
+func signProvenance(t *testing.T, signer *openpgp.Entity, plaintext string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, signer.PrivateKey, nil)
+	if err != nil {
+		t.Fatalf("clearsign.Encode failed: %v", err)
+	}
+	if _, err := w.Write([]byte(plaintext)); err != nil {
+		t.Fatalf("failed to write plaintext: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close clearsign writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyChart(t *testing.T) {
+	entity, err := openpgp.NewEntity("Test Signer", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate PGP entity: %v", err)
+	}
+	keyring := openpgp.EntityList{entity}
+
+	const tarball = "not-really-a-tarball"
+	sum := sha256.Sum256([]byte(tarball))
+	digest := hex.EncodeToString(sum[:])
+
+	plaintext := "name: mychart\nversion: 1.0.0\n...\nfiles:\n  mychart-1.0.0.tgz: sha256:" + digest + "\n"
+	provData := signProvenance(t, entity, plaintext)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts/mychart/1.0.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"mychart","version":"1.0.0","urls":["charts/mychart-1.0.0.tgz"]}`))
+		case "/charts/mychart-1.0.0.tgz":
+			w.Write([]byte(tarball))
+		case "/charts/mychart-1.0.0.tgz.prov":
+			w.Write(provData)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	name, version, empty := "mychart", "1.0.0", ""
+	ci := &ChartInfo{Name: &name, Version: &version, Org: &empty, Repo: &empty}
+
+	result, err := c.ChartService.VerifyChart(context.Background(), ci, keyring)
+	if err != nil {
+		t.Fatalf("VerifyChart failed: %v", err)
+	}
+	if result.FileHash != digest {
+		t.Errorf("expected FileHash %q, got %q", digest, result.FileHash)
+	}
+	if result.FileName != "mychart-1.0.0.tgz" {
+		t.Errorf("expected FileName %q, got %q", "mychart-1.0.0.tgz", result.FileName)
+	}
+}
+
+func TestVerifyChartDigestMismatch(t *testing.T) {
+	entity, err := openpgp.NewEntity("Test Signer", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate PGP entity: %v", err)
+	}
+	keyring := openpgp.EntityList{entity}
+
+	const tarball = "not-really-a-tarball"
+	plaintext := "name: mychart\nversion: 1.0.0\n...\nfiles:\n  mychart-1.0.0.tgz: sha256:0000000000000000000000000000000000000000000000000000000000000000\n"
+	provData := signProvenance(t, entity, plaintext)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/charts/mychart/1.0.0":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name":"mychart","version":"1.0.0","urls":["charts/mychart-1.0.0.tgz"]}`))
+		case "/charts/mychart-1.0.0.tgz":
+			w.Write([]byte(tarball))
+		case "/charts/mychart-1.0.0.tgz.prov":
+			w.Write(provData)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	name, version, empty := "mychart", "1.0.0", ""
+	ci := &ChartInfo{Name: &name, Version: &version, Org: &empty, Repo: &empty}
+
+	if _, err := c.ChartService.VerifyChart(context.Background(), ci, keyring); err == nil {
+		t.Fatal("expected VerifyChart to fail on sha256 mismatch, got nil error")
+	}
+}
@@ -0,0 +1,105 @@
+package chartmuseum
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client.Do retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made, including the
+	// first. Values <= 1 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff used after the first failed attempt,
+	// doubling on each subsequent attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff. Zero means no cap.
+	MaxDelay time.Duration
+	// Jitter randomizes each computed backoff between 0 and the
+	// otherwise-deterministic delay, to avoid thundering-herd retries.
+	Jitter bool
+	// RetryableStatusCodes lists the HTTP status codes that are retried.
+	// Nil falls back to 429, 502, 503 and 504.
+	RetryableStatusCodes map[int]bool
+}
+
+// DefaultRetryPolicy is applied to every Client unless overridden with
+// WithRetry.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   250 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      true,
+		RetryableStatusCodes: map[int]bool{
+			http.StatusTooManyRequests:    true,
+			http.StatusBadGateway:         true,
+			http.StatusServiceUnavailable: true,
+			http.StatusGatewayTimeout:     true,
+		},
+	}
+}
+
+// WithRetry replaces the Client's RetryPolicy.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) error {
+		c.retry = policy
+		return nil
+	}
+}
+
+func (p RetryPolicy) shouldRetry(err error) bool {
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		// A non-APIError means the request never got a response at all
+		// (network error, connection reset, etc.) - also worth retrying.
+		return true
+	}
+	if p.RetryableStatusCodes == nil {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode == http.StatusBadGateway ||
+			apiErr.StatusCode == http.StatusServiceUnavailable ||
+			apiErr.StatusCode == http.StatusGatewayTimeout
+	}
+	return p.RetryableStatusCodes[apiErr.StatusCode]
+}
+
+// backoff computes the delay before the (attempt+1)th attempt, honoring
+// Retry-After on the prior response's 429/503 if present.
+func (p RetryPolicy) backoff(attempt int, resp *Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := retryAfter(resp.Header); ok {
+			return d
+		}
+	}
+
+	delay := p.BaseDelay << uint(attempt)
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter && delay > 0 {
+		delay = time.Duration(rand.Int63n(int64(delay)))
+	}
+	return delay
+}
+
+// retryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP-date.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
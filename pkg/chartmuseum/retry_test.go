@@ -0,0 +1,86 @@
+package chartmuseum
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	if !policy.shouldRetry(errors.New("connection reset")) {
+		t.Error("expected a non-APIError to be retryable")
+	}
+	if !policy.shouldRetry(&APIError{StatusCode: http.StatusServiceUnavailable}) {
+		t.Error("expected 503 to be retryable under the default policy")
+	}
+	if policy.shouldRetry(&APIError{StatusCode: http.StatusNotFound}) {
+		t.Error("expected 404 to not be retryable under the default policy")
+	}
+
+	custom := RetryPolicy{RetryableStatusCodes: map[int]bool{http.StatusConflict: true}}
+	if !custom.shouldRetry(&APIError{StatusCode: http.StatusConflict}) {
+		t.Error("expected 409 to be retryable under the custom policy")
+	}
+	if custom.shouldRetry(&APIError{StatusCode: http.StatusServiceUnavailable}) {
+		t.Error("expected 503 to not be retryable under the custom policy, which only lists 409")
+	}
+}
+
+func TestRetryPolicyBackoffRespectsMaxDelayAndJitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 150 * time.Millisecond, Jitter: false}
+	if d := policy.backoff(0, nil); d != 100*time.Millisecond {
+		t.Errorf("attempt 0: expected 100ms, got %v", d)
+	}
+	if d := policy.backoff(1, nil); d != 150*time.Millisecond {
+		t.Errorf("attempt 1: expected delay capped to MaxDelay 150ms, got %v", d)
+	}
+
+	jittered := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: true}
+	for i := 0; i < 10; i++ {
+		if d := jittered.backoff(0, nil); d < 0 || d > 100*time.Millisecond {
+			t.Fatalf("jittered delay %v out of [0, 100ms] range", d)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffHonorsRetryAfter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second}
+	resp := &Response{Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"2"}}}}
+	if d := policy.backoff(0, resp); d != 2*time.Second {
+		t.Errorf("expected Retry-After to override backoff, got %v", d)
+	}
+}
+
+func TestClientDoRetriesOn503ThenSucceeds(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(ts.URL, http.DefaultClient, WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	req, err := c.NewRequest("GET", "health", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if _, err := c.Do(context.Background(), req); err != nil {
+		t.Fatalf("Do failed after retry: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
@@ -0,0 +1,142 @@
+package chartmuseum
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// UploadChartDir loads the chart directory at dir, packages it as a gzipped
+// tarball in memory, and uploads it - without ever writing to a temp
+// directory on disk. This makes it usable on read-only filesystems and lets
+// it be piped from CI systems.
+func (s *ChartService) UploadChartDir(ctx context.Context, c *ChartInfo, dir string) (*Response, error) {
+	loaded, err := chartutil.LoadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error while loading Chart directory: %q", dir)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeChartArchive(pw, loaded))
+	}()
+
+	return s.UploadChartReader(ctx, c, pr, -1)
+}
+
+// UploadChartReader uploads a chart tarball read from r. size may be -1 if
+// the tarball's length isn't known up front (e.g. when streamed from
+// UploadChartDir), in which case the upload is sent chunked.
+func (s *ChartService) UploadChartReader(ctx context.Context, c *ChartInfo, r io.Reader, size int64) (*Response, error) {
+	prefix, err := scopedPrefix(c)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewUploadRequest(prefix+"api/charts", r, size, "application/gzip")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed creating upload request")
+	}
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return resp, errors.Wrap(err, "Failed to do upload request")
+	}
+	return resp, nil
+}
+
+// writeChartArchive writes c as a gzipped tarball to w, mirroring the layout
+// chartutil.Save produces on disk (<name>/Chart.yaml, <name>/values.yaml,
+// <name>/templates/..., plus any other chart files). Dependencies are packed
+// recursively as nested <name>/charts/<sub>-<version>.tgz archives.
+func writeChartArchive(w io.Writer, c *chart.Chart) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeChartTar(tw, c); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeChartTar writes c's Chart.yaml, values.yaml, templates and other files
+// into tw, then recurses into c.Dependencies, packing each subchart as a
+// nested gzipped tarball under <name>/charts/<sub>-<version>.tgz.
+func writeChartTar(tw *tar.Writer, c *chart.Chart) error {
+	if c.Metadata == nil || c.Metadata.Name == "" {
+		return errors.New("Chart has no name")
+	}
+	base := c.Metadata.Name
+
+	writeFile := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{
+			Name: filepath.Join(base, name),
+			Mode: 0644,
+			Size: int64(len(data)),
+		}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	metadata, err := yaml.Marshal(c.Metadata)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal Chart.yaml")
+	}
+	if err := writeFile("Chart.yaml", metadata); err != nil {
+		return err
+	}
+
+	if c.Values != nil && c.Values.Raw != "" {
+		if err := writeFile("values.yaml", []byte(c.Values.Raw)); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range c.Files {
+		if err := writeFile(f.TypeUrl, f.Value); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range c.Templates {
+		if err := writeFile(t.Name, t.Data); err != nil {
+			return err
+		}
+	}
+
+	for _, dep := range c.Dependencies {
+		if dep.Metadata == nil || dep.Metadata.Name == "" {
+			return errors.New("Chart dependency has no name")
+		}
+		var buf bytes.Buffer
+		if err := writeChartArchive(&buf, dep); err != nil {
+			return errors.Wrapf(err, "Failed to package dependency %q", dep.Metadata.Name)
+		}
+		name := filepath.Join(base, "charts", fmt.Sprintf("%s-%s.tgz", dep.Metadata.Name, dep.Metadata.Version))
+		if err := tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0644,
+			Size: int64(buf.Len()),
+		}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
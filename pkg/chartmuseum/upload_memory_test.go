@@ -0,0 +1,115 @@
+package chartmuseum
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("failed to parse URL %q: %v", raw, err)
+	}
+	return u
+}
+
+// tarEntries reads a gzipped tarball and returns its entry names.
+func tarEntries(t *testing.T, data []byte) []string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to open gzip reader: %v", err)
+	}
+	defer gz.Close()
+
+	var names []string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar entry: %v", err)
+		}
+		names = append(names, hdr.Name)
+	}
+	return names
+}
+
+func TestWriteChartArchivePacksDependencies(t *testing.T) {
+	dep := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "mysql", Version: "1.2.3"},
+	}
+	c := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: "wordpress", Version: "0.1.0"},
+		Dependencies: []*chart.Chart{dep},
+	}
+
+	var buf bytes.Buffer
+	if err := writeChartArchive(&buf, c); err != nil {
+		t.Fatalf("writeChartArchive failed: %v", err)
+	}
+
+	names := tarEntries(t, buf.Bytes())
+	var found bool
+	for _, name := range names {
+		if name == "wordpress/charts/mysql-1.2.3.tgz" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected wordpress/charts/mysql-1.2.3.tgz in archive, got %v", names)
+	}
+}
+
+func TestNewMultipartUploadRequestPartNames(t *testing.T) {
+	req, err := (&Client{BaseURL: mustParseURL(t, "http://example.com/"), UserAgent: userAgent}).NewMultipartUploadRequest(
+		"api/charts",
+		UploadPart{Field: "chart", FileName: "mychart-1.0.0.tgz", Reader: bytes.NewReader([]byte("chart-bytes"))},
+		UploadPart{Field: "prov", FileName: "mychart-1.0.0.tgz.prov", Reader: bytes.NewReader([]byte("prov-bytes"))},
+	)
+	if err != nil {
+		t.Fatalf("NewMultipartUploadRequest failed: %v", err)
+	}
+
+	mr, err := req.MultipartReader()
+	if err != nil {
+		t.Fatalf("failed to read multipart body: %v", err)
+	}
+
+	var fields, names []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read part: %v", err)
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read part body: %v", err)
+		}
+		if len(data) == 0 {
+			t.Errorf("part %q has empty body", part.FormName())
+		}
+		fields = append(fields, part.FormName())
+		names = append(names, part.FileName())
+	}
+
+	if len(fields) != 2 || fields[0] != "chart" || fields[1] != "prov" {
+		t.Errorf("unexpected part fields: %v", fields)
+	}
+	if len(names) != 2 || names[0] != "mychart-1.0.0.tgz" || names[1] != "mychart-1.0.0.tgz.prov" {
+		t.Errorf("unexpected part file names: %v", names)
+	}
+}